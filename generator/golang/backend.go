@@ -16,9 +16,10 @@ package golang
 
 import (
 	"bytes"
+	"context"
 	"fmt"
-	"go/format"
 	"path/filepath"
+	"strconv"
 	"strings"
 	"sync"
 	"text/template"
@@ -50,6 +51,28 @@ type GoBackend struct {
 
 	utils *CodeUtils
 	funcs template.FuncMap
+
+	// tplSources holds the raw template bodies used to build tpl, so
+	// that each parallel worker can parse its own copy bound to its
+	// own FuncMap (see newRenderer).
+	tplSources []string
+
+	// parallel is the worker pool size for executeTemplates, set via
+	// the `parallel=N` generator parameter. 0 and 1 both mean "run
+	// serially", matching the pre-existing behavior.
+	parallel int
+
+	// cache backs incremental generation, set via the `incremental=
+	// <cachedir>` generator parameter. nil means incremental mode is
+	// off and every file is rendered and emitted unconditionally.
+	cache          *incrementalCache
+	incrementalDir string
+	invalidateOn   []string
+
+	// postProcessors is the chain PostProcess runs over generated
+	// files, set via the `postprocess=...` generator parameter. Empty
+	// means the default: a single gofmt pass (warn on failure).
+	postProcessors []registeredProcessor
 }
 
 // Name implements the Backend interface.
@@ -62,6 +85,18 @@ func (g *GoBackend) Lang() string {
 	return "Go"
 }
 
+// extendedParams documents the generator parameters read directly by
+// parseExtendedParameters. They control how executeTemplates and
+// PostProcess run rather than what CodeUtils generates, so they live
+// outside allParams, but are still surfaced through Options() so that
+// -g go:help stays accurate.
+var extendedParams = []plugin.Option{
+	{Name: "parallel", Desc: "Render files across N worker goroutines. 0 or 1 (the default) runs serially."},
+	{Name: "incremental", Desc: "Skip rendering/emitting files whose content hasn't changed, using a cache stored under the given directory."},
+	{Name: "invalidate-on", Desc: "Colon-separated extra files (e.g. custom templates) whose content invalidates the incremental cache."},
+	{Name: "postprocess", Desc: "Comma-separated post-processor chain: gofmt, goimports, or cmd:<name> to shell out. Prefix an entry with '!' to make its failure fatal."},
+}
+
 // Options implements the Backend interface.
 func (g *GoBackend) Options() (opts []plugin.Option) {
 	for _, p := range allParams {
@@ -70,6 +105,7 @@ func (g *GoBackend) Options() (opts []plugin.Option) {
 			Desc: p.desc,
 		})
 	}
+	opts = append(opts, extendedParams...)
 	return opts
 }
 
@@ -89,6 +125,10 @@ func (g *GoBackend) Generate(req *plugin.Request, log backend.LogFunc) *plugin.R
 	g.res = plugin.NewResponse()
 	g.log = log
 	g.prepareUtilities()
+	g.parseExtendedParameters()
+	if g.err != nil {
+		return g.buildResponse()
+	}
 	if g.utils.Features().TrimIDL {
 		g.log.Warn("You Are Using IDL Trimmer")
 		tr, err := trim.TrimAST(&trim.TrimASTArg{Ast: req.AST, TrimMethods: nil, Preserve: nil})
@@ -113,6 +153,11 @@ func (g *GoBackend) Generate(req *plugin.Request, log backend.LogFunc) *plugin.R
 		g.fillRequisitions()
 		g.executeTemplates()
 	}
+	if g.cache != nil && g.err == nil {
+		if err := g.cache.flush(); err != nil {
+			g.log.Warn(err.Error())
+		}
+	}
 	return g.buildResponse()
 }
 
@@ -135,6 +180,52 @@ func (g *GoBackend) prepareUtilities() {
 	g.funcs["Version"] = func() string { return g.req.Version }
 }
 
+// parseExtendedParameters scans the raw generator parameters for the
+// knobs that control how executeTemplates runs, rather than what it
+// generates (e.g. `parallel`). These are orthogonal to CodeUtils'
+// Features/options handling, so they're parsed directly here.
+func (g *GoBackend) parseExtendedParameters() {
+	if g.err != nil {
+		return
+	}
+
+	for _, p := range g.req.GeneratorParameters {
+		key, val := p, ""
+		if idx := strings.IndexByte(p, '='); idx >= 0 {
+			key, val = p[:idx], p[idx+1:]
+		}
+
+		switch key {
+		case "parallel":
+			n, err := strconv.Atoi(val)
+			if err != nil || n < 0 {
+				g.err = fmt.Errorf("invalid parallel parameter %q: must be a non-negative integer", val)
+				return
+			}
+			g.parallel = n
+		case "incremental":
+			if val == "" {
+				g.err = fmt.Errorf("incremental parameter requires a cache directory")
+				return
+			}
+			g.incrementalDir = val
+		case "invalidate-on":
+			if val != "" {
+				g.invalidateOn = append(g.invalidateOn, strings.Split(val, ":")...)
+			}
+		case "postprocess":
+			if err := g.parsePostProcessors(val); err != nil {
+				g.err = err
+				return
+			}
+		}
+	}
+
+	if g.incrementalDir != "" {
+		g.cache = newIncrementalCache(g.incrementalDir, g.req.Version, g.invalidateOn, g.log.Warn)
+	}
+}
+
 func (g *GoBackend) prepareTemplates() {
 	if g.err != nil {
 		return
@@ -153,6 +244,7 @@ func (g *GoBackend) prepareTemplates() {
 		all = template.Must(all.Parse(tpl))
 	}
 	g.tpl = all
+	g.tplSources = tpls
 
 	g.refTpl = template.Must(template.New("thrift-ref").Funcs(g.funcs).Parse(ref_tpl.File))
 	g.reflectionTpl = template.Must(template.New("thrift-reflection").Funcs(g.funcs).Parse(reflection_tpl.File))
@@ -181,41 +273,240 @@ func (g *GoBackend) executeTemplates() {
 		close(trees)
 	}
 
+	var asts []*parser.Thrift
 	for ast := range trees {
 		if processed[ast] {
 			continue
 		}
 		processed[ast] = true
-		g.log.Info("Processing", ast.Filename)
+		asts = append(asts, ast)
+	}
 
-		if g.err = g.renderOneFile(ast); g.err != nil {
-			break
+	if g.parallel < 2 {
+		r := g.mainRenderer()
+		for _, ast := range asts {
+			g.log.Info("Processing", ast.Filename)
+			if g.err = g.renderOneFile(r, ast, &g.res.Contents); g.err != nil {
+				return
+			}
 		}
+		return
 	}
+
+	g.err = g.executeTemplatesParallel(asts)
 }
 
-func (g *GoBackend) renderOneFile(ast *parser.Thrift) error {
-	keepName := g.utils.Features().KeepCodeRefName
-	path := g.utils.CombineOutputPath(g.req.OutputPath, ast)
-	filename := filepath.Join(path, g.utils.GetFilename(ast))
-	localScope, refScope, err := BuildRefScope(g.utils, ast)
+// renderer bundles the CodeUtils instance and template set used to
+// render a single file. executeTemplatesParallel gives each worker its
+// own renderer so that CodeUtils.SetRootScope, which is stateful,
+// never races across goroutines.
+type renderer struct {
+	utils            *CodeUtils
+	tpl              *template.Template
+	refTpl           *template.Template
+	reflectionTpl    *template.Template
+	reflectionRefTpl *template.Template
+}
+
+// mainRenderer wraps GoBackend's own CodeUtils and templates, used by
+// the serial path so that parallel=0/1 behaves exactly as before.
+func (g *GoBackend) mainRenderer() *renderer {
+	return &renderer{
+		utils:            g.utils,
+		tpl:              g.tpl,
+		refTpl:           g.refTpl,
+		reflectionTpl:    g.reflectionTpl,
+		reflectionRefTpl: g.reflectionRefTpl,
+	}
+}
+
+// newRenderer clones g.utils and reparses the template sets against a
+// FuncMap built from the clone, giving the caller a fully independent
+// renderer that can run concurrently with others.
+func (g *GoBackend) newRenderer() (*renderer, error) {
+	u := g.utils.Clone()
+	funcs := u.BuildFuncMap()
+	funcs["Version"] = func() string { return g.req.Version }
+
+	all := template.New("thrift").Funcs(funcs)
+	for _, tpl := range g.tplSources {
+		var err error
+		if all, err = all.Parse(tpl); err != nil {
+			return nil, err
+		}
+	}
+	refTpl, err := template.New("thrift-ref").Funcs(funcs).Parse(ref_tpl.File)
+	if err != nil {
+		return nil, err
+	}
+	reflectionTpl, err := template.New("thrift-reflection").Funcs(funcs).Parse(reflection_tpl.File)
+	if err != nil {
+		return nil, err
+	}
+	reflectionRefTpl, err := template.New("thrift-reflection-util").Funcs(funcs).Parse(reflection_tpl.FileRef)
+	if err != nil {
+		return nil, err
+	}
+	return &renderer{
+		utils:            u,
+		tpl:              all,
+		refTpl:           refTpl,
+		reflectionTpl:    reflectionTpl,
+		reflectionRefTpl: reflectionRefTpl,
+	}, nil
+}
+
+// runIndexed runs fn(idx) for every idx in [0,n) across up to workers
+// goroutines at a time, pulling jobs from a shared channel so that a
+// goroutine never sits idle while work remains. It returns the first
+// error any fn call produces; that error cancels an internal context,
+// after which the feed loop and the workers stop taking further jobs.
+// fn receives no context, so an fn call that is already running is not
+// interrupted and runs to completion.
+// Job scheduling is deliberately unordered; callers that need ordered
+// output should have fn write into a pre-indexed slot keyed by idx.
+func runIndexed(n, workers int, fn func(idx int) error) error {
+	if workers > n {
+		workers = n
+	}
+	if workers < 1 {
+		return nil
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	jobs := make(chan int)
+	var (
+		wg       sync.WaitGroup
+		errOnce  sync.Once
+		firstErr error
+	)
+	fail := func(err error) {
+		errOnce.Do(func() {
+			firstErr = err
+			cancel()
+		})
+	}
+
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case idx, ok := <-jobs:
+					if !ok {
+						return
+					}
+					if err := fn(idx); err != nil {
+						fail(err)
+						return
+					}
+				}
+			}
+		}()
+	}
+
+feed:
+	for idx := 0; idx < n; idx++ {
+		select {
+		case jobs <- idx:
+		case <-ctx.Done():
+			break feed
+		}
+	}
+	close(jobs)
+	wg.Wait()
+
+	return firstErr
+}
+
+// executeTemplatesParallel renders asts across a pool of g.parallel
+// workers. Each goroutine that touches a renderer puts it back in
+// renderers when done, so at most g.parallel renderers ever exist
+// regardless of how jobs happen to be scheduled across workers.
+// Results are written into slots pre-indexed by input order so that
+// g.res.Contents ends up in the same order as the serial path
+// regardless of which worker finishes first. After the first error,
+// runIndexed stops handing out the remaining jobs; renders already in
+// progress are not interrupted.
+func (g *GoBackend) executeTemplatesParallel(asts []*parser.Thrift) error {
+	renderers := make(chan *renderer, g.parallel)
+	for i := 0; i < g.parallel && i < len(asts); i++ {
+		r, err := g.newRenderer()
+		if err != nil {
+			return err
+		}
+		renderers <- r
+	}
+
+	slots := make([][]*plugin.Generated, len(asts))
+	err := runIndexed(len(asts), g.parallel, func(idx int) error {
+		r := <-renderers
+		defer func() { renderers <- r }()
+
+		ast := asts[idx]
+		g.log.Info("Processing", ast.Filename)
+		var out []*plugin.Generated
+		if err := g.renderOneFile(r, ast, &out); err != nil {
+			return err
+		}
+		slots[idx] = out
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, out := range slots {
+		g.res.Contents = append(g.res.Contents, out...)
+	}
+	return nil
+}
+
+func (g *GoBackend) renderOneFile(r *renderer, ast *parser.Thrift, out *[]*plugin.Generated) (err error) {
+	keepName := r.utils.Features().KeepCodeRefName
+	path := r.utils.CombineOutputPath(g.req.OutputPath, ast)
+	filename := filepath.Join(path, r.utils.GetFilename(ast))
+
+	if g.cache != nil {
+		astKey := "ast:" + filename
+		astHash, hashErr := hashAST(ast, g.cache.salt, r.utils.Features())
+		if hashErr != nil {
+			return hashErr
+		}
+		if g.cache.unchanged(astKey, astHash) {
+			g.log.Info("Skipping unchanged AST", ast.Filename)
+			return nil
+		}
+		defer func() {
+			if err == nil {
+				g.cache.remember(astKey, astHash)
+			}
+		}()
+	}
+
+	localScope, refScope, err := BuildRefScope(r.utils, ast)
 	if err != nil {
 		return err
 	}
-	err = g.renderByTemplate(localScope, g.tpl, filename)
+	err = g.renderByTemplate(r, localScope, r.tpl, filename, out)
 	if err != nil {
 		return err
 	}
-	err = g.renderByTemplate(refScope, g.refTpl, ToRefFilename(keepName, filename))
+	err = g.renderByTemplate(r, refScope, r.refTpl, ToRefFilename(keepName, filename), out)
 	if err != nil {
 		return err
 	}
-	if g.utils.Features().WithReflection {
-		err = g.renderByTemplate(refScope, g.reflectionRefTpl, ToReflectionRefFilename(keepName, filename))
+	if r.utils.Features().WithReflection {
+		err = g.renderByTemplate(r, refScope, r.reflectionRefTpl, ToReflectionRefFilename(keepName, filename), out)
 		if err != nil {
 			return err
 		}
-		return g.renderByTemplate(localScope, g.reflectionTpl, ToReflectionFilename(filename))
+		return g.renderByTemplate(r, localScope, r.reflectionTpl, ToReflectionFilename(filename), out)
 	}
 	return nil
 }
@@ -260,12 +551,12 @@ var poolBuffer = sync.Pool{
 	},
 }
 
-func (g *GoBackend) renderByTemplate(scope *Scope, executeTpl *template.Template, filename string) error {
+func (g *GoBackend) renderByTemplate(r *renderer, scope *Scope, executeTpl *template.Template, filename string, out *[]*plugin.Generated) error {
 	if scope == nil {
 		return nil
 	}
 	// if scope has no content, just skip and don't generate this file
-	if g.utils.Features().SkipEmpty {
+	if r.utils.Features().SkipEmpty {
 		if scope.IsEmpty() {
 			return nil
 		}
@@ -276,15 +567,13 @@ func (g *GoBackend) renderByTemplate(scope *Scope, executeTpl *template.Template
 
 	w.Reset()
 
-	g.utils.SetRootScope(scope)
+	r.utils.SetRootScope(scope)
 	err := executeTpl.ExecuteTemplate(w, executeTpl.Name(), scope)
 	if err != nil {
 		return fmt.Errorf("%s: %w", filename, err)
 	}
-	g.res.Contents = append(g.res.Contents, &plugin.Generated{
-		Content: w.String(),
-		Name:    &filename,
-	})
+	content := append([]byte(nil), w.Bytes()...)
+
 	imports, err := scope.ResolveImports()
 	if err != nil {
 		return err
@@ -294,14 +583,48 @@ func (g *GoBackend) renderByTemplate(scope *Scope, executeTpl *template.Template
 	if err != nil {
 		return fmt.Errorf("%s: %w", filename, err)
 	}
+	importsContent := append([]byte(nil), w.Bytes()...)
+
+	if g.cache != nil {
+		hash := g.cache.sum(content, importsContent, r.utils.Features())
+		if g.cache.unchanged(filename, hash) {
+			g.log.Info("Skipping unchanged output", filename)
+			return nil
+		}
+		defer g.cache.remember(filename, hash)
+	}
+
+	*out = append(*out, &plugin.Generated{
+		Content: string(content),
+		Name:    &filename,
+	})
 	point := "imports"
-	g.res.Contents = append(g.res.Contents, &plugin.Generated{
-		Content:        w.String(),
+	*out = append(*out, &plugin.Generated{
+		Content:        string(importsContent),
 		InsertionPoint: &point,
 	})
 	return nil
 }
 
+// Clone returns a shallow copy of cu for use by a single goroutine.
+// CodeUtils carries render-time state (notably the root scope set by
+// SetRootScope), so concurrent renders must each own their own clone
+// rather than share cu directly.
+//
+// This is a shallow copy: it isolates CodeUtils' own top-level fields,
+// including the pointer SetRootScope assigns, but it would not isolate
+// a field that some other CodeUtils method mutates in place (e.g.
+// appends into a shared slice/map) rather than replacing wholesale the
+// way SetRootScope replaces the root scope pointer. TestExecuteTemplatesParallelRace
+// in backend_test.go exercises executeTemplatesParallel/renderOneFile
+// end-to-end under `go test -race` to catch exactly that; re-run it
+// after adding or changing any CodeUtils field touched during
+// rendering.
+func (cu *CodeUtils) Clone() *CodeUtils {
+	clone := *cu
+	return &clone
+}
+
 func (g *GoBackend) buildResponse() *plugin.Response {
 	if g.err != nil {
 		return plugin.BuildErrorResponse(g.err.Error())
@@ -309,19 +632,35 @@ func (g *GoBackend) buildResponse() *plugin.Response {
 	return g.res
 }
 
-// PostProcess implements the backend.PostProcessor interface to do
-// source formatting before writing files out.
+// PostProcess implements the backend.PostProcessor interface, running
+// the configured chain of PostProcessors (gofmt by default) over each
+// generated file before it's written out.
 func (g *GoBackend) PostProcess(path string, content []byte) ([]byte, error) {
-	if g.utils.Features().NoFmt {
+	if filepath.Ext(path) != ".go" {
 		return content, nil
 	}
-	switch filepath.Ext(path) {
-	case ".go":
-		if formated, err := format.Source(content); err != nil {
-			g.log.Warn(fmt.Sprintf("Failed to format %s: %s", path, err.Error()))
-		} else {
-			content = formated
+
+	// NoFmt only suppresses the default gofmt fallback that ran
+	// unconditionally before postprocess= existed; it must not silently
+	// drop a chain the user explicitly configured.
+	processors := g.postProcessors
+	if len(processors) == 0 {
+		if g.utils.Features().NoFmt {
+			return content, nil
+		}
+		processors = []registeredProcessor{{proc: &gofmtProcessor{}, hardFail: false}}
+	}
+
+	for _, rp := range processors {
+		processed, err := rp.proc.Process(path, content)
+		if err != nil {
+			if rp.hardFail {
+				return nil, fmt.Errorf("%s: %s: %w", rp.proc.Name(), path, err)
+			}
+			g.log.Warn(fmt.Sprintf("Failed to run %s on %s: %s", rp.proc.Name(), path, err.Error()))
+			continue
 		}
+		content = processed
 	}
 	return content, nil
 }
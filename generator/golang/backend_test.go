@@ -0,0 +1,171 @@
+// Copyright 2024 CloudWeGo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package golang
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/cloudwego/thriftgo/parser"
+	"github.com/cloudwego/thriftgo/plugin"
+)
+
+func TestRunIndexedOrdering(t *testing.T) {
+	const n = 50
+	results := make([]int, n)
+
+	err := runIndexed(n, 8, func(idx int) error {
+		results[idx] = idx * idx
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("runIndexed returned unexpected error: %v", err)
+	}
+	for idx, got := range results {
+		if want := idx * idx; got != want {
+			t.Errorf("slot %d = %d, want %d", idx, got, want)
+		}
+	}
+}
+
+func TestRunIndexedConcurrencyCap(t *testing.T) {
+	const n, workers = 40, 4
+
+	var mu sync.Mutex
+	var inFlight, maxInFlight int32
+
+	err := runIndexed(n, workers, func(idx int) error {
+		cur := atomic.AddInt32(&inFlight, 1)
+		mu.Lock()
+		if cur > maxInFlight {
+			maxInFlight = cur
+		}
+		mu.Unlock()
+		time.Sleep(time.Millisecond)
+		atomic.AddInt32(&inFlight, -1)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("runIndexed returned unexpected error: %v", err)
+	}
+	if maxInFlight > workers {
+		t.Errorf("observed %d jobs in flight at once, want <= %d", maxInFlight, workers)
+	}
+}
+
+func TestRunIndexedCancelsOnFirstError(t *testing.T) {
+	const n = 100
+	boom := errors.New("boom")
+
+	var started int32
+	err := runIndexed(n, 4, func(idx int) error {
+		atomic.AddInt32(&started, 1)
+		if idx == 0 {
+			return boom
+		}
+		// Slow enough that, once idx 0 cancels the run, the feed loop
+		// and idle workers stop picking up new jobs well before all
+		// n-1 remaining jobs would otherwise complete.
+		time.Sleep(5 * time.Millisecond)
+		return nil
+	})
+	if !errors.Is(err, boom) {
+		t.Fatalf("runIndexed error = %v, want %v", err, boom)
+	}
+	if got := int(started); got >= n {
+		t.Errorf("runIndexed ran all %d jobs after the first error; cancellation should have skipped some", n)
+	}
+}
+
+func TestRunIndexedNoWork(t *testing.T) {
+	if err := runIndexed(0, 4, func(idx int) error {
+		t.Fatalf("fn should not run for n=0")
+		return nil
+	}); err != nil {
+		t.Fatalf("runIndexed(0, ...) = %v, want nil", err)
+	}
+}
+
+type raceTestLogger struct{}
+
+func (raceTestLogger) Info(v ...interface{}) {}
+func (raceTestLogger) Warn(v ...interface{}) {}
+
+var raceTestIdxRe = regexp.MustCompile(`idx(\d+)`)
+
+// TestExecuteTemplatesParallelRace exercises the concurrency-sensitive
+// path this request is actually about -- executeTemplatesParallel and
+// renderOneFile driving real CodeUtils clones and template execution
+// across goroutines -- rather than just the generic runIndexed
+// scheduler above. Run with `go test -race` to catch a CodeUtils field
+// that Clone's shallow copy fails to isolate (see the caveat on
+// CodeUtils.Clone); this sandbox's tree has no go.mod, so that can
+// only be confirmed against a full checkout.
+func TestExecuteTemplatesParallelRace(t *testing.T) {
+	const workers, n = 8, 32
+
+	g := &GoBackend{
+		req: &plugin.Request{Version: "race-test"},
+		res: plugin.NewResponse(),
+		log: raceTestLogger{},
+	}
+	g.utils = NewCodeUtils(g.log)
+	if err := g.utils.HandleOptions(nil); err != nil {
+		t.Fatalf("HandleOptions: %v", err)
+	}
+	g.funcs = g.utils.BuildFuncMap()
+	g.funcs["Version"] = func() string { return g.req.Version }
+	g.tplSources = []string{"// generated for {{Version}}\n{{define \"Imports\"}}{{end}}"}
+	g.parallel = workers
+
+	asts := make([]*parser.Thrift, n)
+	for i := range asts {
+		asts[i] = &parser.Thrift{Filename: fmt.Sprintf("idx%03d.thrift", i)}
+	}
+
+	if err := g.executeTemplatesParallel(asts); err != nil {
+		t.Fatalf("executeTemplatesParallel: %v", err)
+	}
+	if len(g.res.Contents) == 0 {
+		t.Fatalf("expected at least one Generated entry")
+	}
+
+	// executeTemplatesParallel promises g.res.Contents comes back in
+	// input order regardless of which worker finishes first. The order
+	// comes from the pre-indexed slots and each name from its AST's
+	// filename, so this check does not see a racing SetRootScope; that
+	// is left to `go test -race`.
+	lastIdx := -1
+	for _, c := range g.res.Contents {
+		if c.Name == nil {
+			continue
+		}
+		m := raceTestIdxRe.FindStringSubmatch(*c.Name)
+		if m == nil {
+			t.Fatalf("output name %q is missing the expected idx marker", *c.Name)
+		}
+		idx, _ := strconv.Atoi(m[1])
+		if idx < lastIdx {
+			t.Fatalf("output order regressed: idx %d appeared after idx %d", idx, lastIdx)
+		}
+		lastIdx = idx
+	}
+}
@@ -0,0 +1,188 @@
+// Copyright 2024 CloudWeGo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package golang
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+
+	"github.com/cloudwego/thriftgo/parser"
+)
+
+// cacheFileName is the name of the on-disk incremental generation
+// cache created inside the directory given by the `incremental`
+// generator parameter.
+const cacheFileName = "thriftgo-cache.json"
+
+// incrementalCache tracks the content hash of each generated output
+// (and of each source AST) so that renderOneFile and renderByTemplate
+// can skip work whose result hasn't changed since the last run. It is
+// safe for concurrent use by the executeTemplatesParallel workers.
+type incrementalCache struct {
+	mu      sync.Mutex
+	dir     string
+	path    string
+	entries map[string]string
+	dirty   bool
+
+	// salt mixes the thriftgo version and the invalidate-on file
+	// contents into every hash, so bumping the version or editing a
+	// custom template invalidates the whole cache at once.
+	salt string
+}
+
+// newIncrementalCache loads the cache rooted at dir, if one exists and
+// its salt still matches. invalidateOn is a list of extra file paths
+// (e.g. custom templates) whose content is folded into the salt.
+func newIncrementalCache(dir, version string, invalidateOn []string, warn func(...interface{})) *incrementalCache {
+	c := &incrementalCache{
+		dir:     dir,
+		path:    filepath.Join(dir, cacheFileName),
+		entries: map[string]string{},
+	}
+
+	h := sha256.New()
+	h.Write([]byte(version))
+	sorted := append([]string(nil), invalidateOn...)
+	sort.Strings(sorted)
+	for _, f := range sorted {
+		b, err := os.ReadFile(f)
+		if err != nil {
+			warn(fmt.Sprintf("incremental: failed to read invalidate-on file %q: %s", f, err.Error()))
+			continue
+		}
+		h.Write([]byte(f))
+		h.Write(b)
+	}
+	c.salt = hex.EncodeToString(h.Sum(nil))
+
+	if b, err := os.ReadFile(c.path); err == nil {
+		var onDisk struct {
+			Salt    string            `json:"salt"`
+			Entries map[string]string `json:"entries"`
+		}
+		if json.Unmarshal(b, &onDisk) == nil && onDisk.Salt == c.salt {
+			c.entries = onDisk.Entries
+		}
+	}
+	return c
+}
+
+// sum hashes content and imports together with the cache's salt and
+// any extra parts supplied by the caller (e.g. the active Features
+// bits), producing the key used to detect unchanged output.
+func (c *incrementalCache) sum(content, imports []byte, parts ...interface{}) string {
+	h := sha256.New()
+	h.Write([]byte(c.salt))
+	h.Write(content)
+	h.Write(imports)
+	fmt.Fprintf(h, "%+v", parts)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// unchanged reports whether key was last recorded with the given hash.
+func (c *incrementalCache) unchanged(key, hash string) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return hash != "" && c.entries[key] == hash
+}
+
+// remember records hash as the latest known content for key.
+func (c *incrementalCache) remember(key, hash string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.entries[key] == hash {
+		return
+	}
+	c.entries[key] = hash
+	c.dirty = true
+}
+
+// flush persists the cache to disk if anything changed.
+func (c *incrementalCache) flush() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if !c.dirty {
+		return nil
+	}
+	if err := os.MkdirAll(c.dir, 0o755); err != nil {
+		return fmt.Errorf("incremental: failed to create cache dir %q: %w", c.dir, err)
+	}
+	b, err := json.MarshalIndent(struct {
+		Salt    string            `json:"salt"`
+		Entries map[string]string `json:"entries"`
+	}{c.salt, c.entries}, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(c.path, b, 0o644); err != nil {
+		return fmt.Errorf("incremental: failed to write cache file %q: %w", c.path, err)
+	}
+	c.dirty = false
+	return nil
+}
+
+// hashAST computes a content hash over ast's own fields plus the
+// transitive closure of its includes, so that a change anywhere in
+// the include graph invalidates the files that depend on it. This
+// backs renderOneFile's fast path: an unchanged AST subtree means the
+// template execution for that file can be skipped altogether.
+//
+// salt and parts are mixed in exactly like they are for sum(), so
+// that this fast path honors the same version/invalidate-on/Features
+// invalidation as the per-output cache entries: otherwise toggling a
+// feature flag without touching the IDL or bumping the version would
+// short-circuit renderOneFile before the new output is ever produced.
+func hashAST(ast *parser.Thrift, salt string, parts ...interface{}) (string, error) {
+	h := sha256.New()
+	h.Write([]byte(salt))
+	fmt.Fprintf(h, "%+v", parts)
+
+	seen := map[*parser.Thrift]bool{}
+
+	var walk func(t *parser.Thrift) error
+	walk = func(t *parser.Thrift) error {
+		if seen[t] {
+			return nil
+		}
+		seen[t] = true
+
+		b, err := json.Marshal(t)
+		if err != nil {
+			return fmt.Errorf("hashAST: %s: %w", t.Filename, err)
+		}
+		h.Write(b)
+
+		for _, inc := range t.Includes {
+			if inc.Reference != nil {
+				if err := walk(inc.Reference); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	}
+
+	if err := walk(ast); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
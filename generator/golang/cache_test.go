@@ -0,0 +1,157 @@
+// Copyright 2024 CloudWeGo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package golang
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/cloudwego/thriftgo/parser"
+)
+
+func noopWarn(...interface{}) {}
+
+func TestIncrementalCacheHitMiss(t *testing.T) {
+	dir := t.TempDir()
+	c := newIncrementalCache(dir, "v1", nil, noopWarn)
+
+	sum := c.sum([]byte("content"), []byte("imports"))
+	if c.unchanged("a.go", sum) {
+		t.Fatalf("a fresh cache must not report a key as unchanged before remember")
+	}
+	c.remember("a.go", sum)
+	if !c.unchanged("a.go", sum) {
+		t.Fatalf("expected a.go/%s to be unchanged after remember", sum)
+	}
+
+	otherSum := c.sum([]byte("different content"), []byte("imports"))
+	if c.unchanged("a.go", otherSum) {
+		t.Fatalf("changed content must not be reported as unchanged")
+	}
+}
+
+func TestIncrementalCacheRoundTrip(t *testing.T) {
+	dir := t.TempDir()
+
+	c1 := newIncrementalCache(dir, "v1", nil, noopWarn)
+	sum := c1.sum([]byte("content"), []byte("imports"))
+	c1.remember("a.go", sum)
+	if err := c1.flush(); err != nil {
+		t.Fatalf("flush: %v", err)
+	}
+
+	c2 := newIncrementalCache(dir, "v1", nil, noopWarn)
+	if !c2.unchanged("a.go", sum) {
+		t.Fatalf("expected reloaded cache to remember a.go/%s", sum)
+	}
+}
+
+func TestIncrementalCacheVersionBumpInvalidates(t *testing.T) {
+	dir := t.TempDir()
+
+	c1 := newIncrementalCache(dir, "v1", nil, noopWarn)
+	sum := c1.sum([]byte("content"), []byte("imports"))
+	c1.remember("a.go", sum)
+	if err := c1.flush(); err != nil {
+		t.Fatalf("flush: %v", err)
+	}
+
+	c2 := newIncrementalCache(dir, "v2", nil, noopWarn)
+	if c2.unchanged("a.go", sum) {
+		t.Fatalf("a version bump must invalidate the on-disk cache")
+	}
+}
+
+func TestIncrementalCacheInvalidateOnFileChangeInvalidates(t *testing.T) {
+	dir := t.TempDir()
+	tplPath := filepath.Join(dir, "custom.tmpl")
+	writeFile(t, tplPath, "{{/* v1 */}}")
+
+	c1 := newIncrementalCache(dir, "v1", []string{tplPath}, noopWarn)
+	sum := c1.sum([]byte("content"), []byte("imports"))
+	c1.remember("a.go", sum)
+	if err := c1.flush(); err != nil {
+		t.Fatalf("flush: %v", err)
+	}
+
+	writeFile(t, tplPath, "{{/* v2 */}}")
+	c2 := newIncrementalCache(dir, "v1", []string{tplPath}, noopWarn)
+	if c2.unchanged("a.go", sum) {
+		t.Fatalf("editing an invalidate-on file must invalidate the on-disk cache")
+	}
+}
+
+func TestHashASTSaltAndPartsChangeDigest(t *testing.T) {
+	ast := &parser.Thrift{Filename: "a.thrift"}
+
+	base, err := hashAST(ast, "salt-1")
+	if err != nil {
+		t.Fatalf("hashAST: %v", err)
+	}
+
+	sameAgain, err := hashAST(ast, "salt-1")
+	if err != nil {
+		t.Fatalf("hashAST: %v", err)
+	}
+	if base != sameAgain {
+		t.Fatalf("hashAST must be deterministic for identical inputs")
+	}
+
+	diffSalt, err := hashAST(ast, "salt-2")
+	if err != nil {
+		t.Fatalf("hashAST: %v", err)
+	}
+	if base == diffSalt {
+		t.Fatalf("hashAST must change when the cache salt changes, e.g. after a version bump")
+	}
+
+	diffParts, err := hashAST(ast, "salt-1", "WithReflection=true")
+	if err != nil {
+		t.Fatalf("hashAST: %v", err)
+	}
+	if base == diffParts {
+		t.Fatalf("hashAST must change when the extra parts (e.g. Features()) change")
+	}
+}
+
+func TestHashASTIncludesTransitiveReference(t *testing.T) {
+	included := &parser.Thrift{Filename: "included.thrift"}
+	ast := &parser.Thrift{
+		Filename: "a.thrift",
+		Includes: []*parser.Include{{Path: "included.thrift", Reference: included}},
+	}
+
+	before, err := hashAST(ast, "salt")
+	if err != nil {
+		t.Fatalf("hashAST: %v", err)
+	}
+
+	included.Filename = "included-renamed.thrift"
+	after, err := hashAST(ast, "salt")
+	if err != nil {
+		t.Fatalf("hashAST: %v", err)
+	}
+	if before == after {
+		t.Fatalf("hashAST must change when a transitively included AST changes")
+	}
+}
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatalf("writeFile %s: %v", path, err)
+	}
+}
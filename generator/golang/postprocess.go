@@ -0,0 +1,138 @@
+// Copyright 2024 CloudWeGo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package golang
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// PostProcessor transforms the content of a single generated file
+// before it is written out, e.g. to format it or fix up its imports.
+// Implementations must not retain content beyond the call.
+type PostProcessor interface {
+	// Name identifies the processor in warnings and hard-fail errors.
+	Name() string
+	Process(path string, content []byte) ([]byte, error)
+}
+
+// registeredProcessor pairs a PostProcessor with its configured
+// failure behavior.
+type registeredProcessor struct {
+	proc     PostProcessor
+	hardFail bool
+}
+
+// RegisterPostProcessor appends p to the chain PostProcess runs, in
+// registration order. If hardFail is true, an error from p aborts
+// generation; otherwise it's logged as a warning and the file keeps
+// the content produced by the previous stage.
+func (g *GoBackend) RegisterPostProcessor(p PostProcessor, hardFail bool) {
+	g.postProcessors = append(g.postProcessors, registeredProcessor{proc: p, hardFail: hardFail})
+}
+
+// gofmtProcessor is the default processor, matching the pre-existing
+// hard-coded go/format.Source behavior.
+type gofmtProcessor struct{}
+
+func (*gofmtProcessor) Name() string { return "gofmt" }
+
+func (*gofmtProcessor) Process(path string, content []byte) ([]byte, error) {
+	return format.Source(content)
+}
+
+// execProcessor shells out to an external formatter (e.g. goimports,
+// gofumpt, or a custom linter). gofmt-family tools read from a file
+// argument when one is given and ignore stdin entirely, so content is
+// written to a scratch file and that path is passed as the argument;
+// the formatted result is read back from the tool's stdout, never
+// from disk.
+//
+// "goimports" is deliberately wired to this exec path rather than
+// importing golang.org/x/tools/imports directly: that package pulls in
+// go/packages and a sizeable transitive graph as an unconditional
+// compile-time dependency of generator/golang, just to support one of
+// three optional post-processors. Shelling out to the goimports binary
+// (go install golang.org/x/tools/cmd/goimports@latest) avoids that
+// cost for the large majority of builds that never use it.
+type execProcessor struct {
+	cmd string
+}
+
+func (e *execProcessor) Name() string { return e.cmd }
+
+func (e *execProcessor) Process(path string, content []byte) ([]byte, error) {
+	scratch, err := os.CreateTemp("", "thriftgo-postprocess-*"+filepath.Ext(path))
+	if err != nil {
+		return nil, err
+	}
+	scratchPath := scratch.Name()
+	defer os.Remove(scratchPath)
+
+	_, writeErr := scratch.Write(content)
+	closeErr := scratch.Close()
+	if writeErr != nil {
+		return nil, writeErr
+	}
+	if closeErr != nil {
+		return nil, closeErr
+	}
+
+	cmd := exec.Command(e.cmd, scratchPath)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return nil, fmt.Errorf("%w: %s", err, msg)
+		}
+		return nil, err
+	}
+	return stdout.Bytes(), nil
+}
+
+// parsePostProcessors builds the processor chain described by spec, a
+// comma-separated list of "gofmt", "goimports" or "cmd:<name>" entries.
+// Prefixing an entry with "!" makes its failures hard rather than a
+// warning, e.g. "postprocess=!cmd:my-linter".
+func (g *GoBackend) parsePostProcessors(spec string) error {
+	for _, entry := range strings.Split(spec, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		hardFail := strings.HasPrefix(entry, "!")
+		if hardFail {
+			entry = entry[1:]
+		}
+
+		switch {
+		case entry == "gofmt":
+			g.RegisterPostProcessor(&gofmtProcessor{}, hardFail)
+		case entry == "goimports":
+			g.RegisterPostProcessor(&execProcessor{cmd: "goimports"}, hardFail)
+		case strings.HasPrefix(entry, "cmd:"):
+			g.RegisterPostProcessor(&execProcessor{cmd: strings.TrimPrefix(entry, "cmd:")}, hardFail)
+		default:
+			return fmt.Errorf("unknown postprocess entry %q", entry)
+		}
+	}
+	return nil
+}
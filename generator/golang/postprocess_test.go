@@ -0,0 +1,98 @@
+// Copyright 2024 CloudWeGo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package golang
+
+import (
+	"runtime"
+	"testing"
+)
+
+func TestParsePostProcessorsBuildsChain(t *testing.T) {
+	g := &GoBackend{}
+	if err := g.parsePostProcessors("gofmt,goimports,!cmd:my-linter"); err != nil {
+		t.Fatalf("parsePostProcessors: %v", err)
+	}
+	if len(g.postProcessors) != 3 {
+		t.Fatalf("got %d processors, want 3", len(g.postProcessors))
+	}
+
+	if name := g.postProcessors[0].proc.Name(); name != "gofmt" {
+		t.Errorf("processor 0 = %q, want gofmt", name)
+	}
+	if g.postProcessors[0].hardFail {
+		t.Errorf("gofmt entry should default to warn, not hard-fail")
+	}
+
+	if name := g.postProcessors[1].proc.Name(); name != "goimports" {
+		t.Errorf("processor 1 = %q, want goimports", name)
+	}
+
+	if name := g.postProcessors[2].proc.Name(); name != "my-linter" {
+		t.Errorf("processor 2 = %q, want my-linter", name)
+	}
+	if !g.postProcessors[2].hardFail {
+		t.Errorf("'!' prefixed entry should be hard-fail")
+	}
+}
+
+func TestParsePostProcessorsRejectsUnknownEntry(t *testing.T) {
+	g := &GoBackend{}
+	if err := g.parsePostProcessors("not-a-real-processor"); err == nil {
+		t.Fatalf("expected an error for an unrecognized postprocess entry")
+	}
+}
+
+func TestExecProcessorStreamsViaScratchFile(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("relies on a POSIX cat being on PATH")
+	}
+
+	// cat, given a file argument, prints that file's content to
+	// stdout. If execProcessor mistakenly passed the real destination
+	// path instead of a scratch file containing `content`, this would
+	// either fail (path doesn't exist yet) or return stale content
+	// instead of what we asked it to process.
+	p := &execProcessor{cmd: "cat"}
+	got, err := p.Process("/does/not/exist/output.go", []byte("package main\n"))
+	if err != nil {
+		t.Fatalf("Process: %v", err)
+	}
+	if string(got) != "package main\n" {
+		t.Errorf("Process returned %q, want %q", got, "package main\n")
+	}
+}
+
+func TestExecProcessorFailsOnNonZeroExit(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("relies on a POSIX false being on PATH")
+	}
+
+	p := &execProcessor{cmd: "false"}
+	if _, err := p.Process("output.go", []byte("x")); err == nil {
+		t.Fatalf("expected an error when the configured command exits non-zero")
+	}
+}
+
+func TestGofmtProcessorFormatsSource(t *testing.T) {
+	p := &gofmtProcessor{}
+	got, err := p.Process("output.go", []byte("package main\nfunc  main( ) { }\n"))
+	if err != nil {
+		t.Fatalf("Process: %v", err)
+	}
+	want := "package main\n\nfunc main() {}\n"
+	if string(got) != want {
+		t.Errorf("Process returned %q, want %q", got, want)
+	}
+}